@@ -0,0 +1,137 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CacheEntry хранит всё, что нужно знать о последнем обращении к странице:
+// когда её скачивали, условные заголовки с прошлого ответа сервера, хэш
+// содержимого и результат обработки. Это позволяет на следующем запуске
+// пропускать неизменившиеся страницы и повторять только неудавшиеся, не
+// скачивая заново весь сайт.
+type CacheEntry struct {
+	FetchedAt    time.Time `json:"fetched_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ContentHash  string    `json:"content_hash,omitempty"`
+	Success      bool      `json:"success"`
+	Attempts     int       `json:"attempts"`
+}
+
+// URLCache — персистентный кэш состояния обхода, хранится рядом с data/ в
+// виде одного JSON файла. Безопасен для использования из нескольких воркеров.
+type URLCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]CacheEntry
+}
+
+// LoadURLCache читает кэш из path. Если файла ещё нет — это первый запуск,
+// возвращается пустой кэш.
+func LoadURLCache(path string) (*URLCache, error) {
+	c := &URLCache{path: path, entries: make(map[string]CacheEntry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		return nil, fmt.Errorf("разбор кэша %s: %w", path, err)
+	}
+
+	return c, nil
+}
+
+// Get возвращает сохраненную запись для url, если она есть.
+func (c *URLCache) Get(url string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[url]
+	return entry, ok
+}
+
+// RecordSuccess сохраняет результат успешной обработки url.
+func (c *URLCache) RecordSuccess(url string, etag, lastModified, contentHash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[url] = CacheEntry{
+		FetchedAt:    time.Now(),
+		ETag:         etag,
+		LastModified: lastModified,
+		ContentHash:  contentHash,
+		Success:      true,
+	}
+}
+
+// RecordFailure отмечает, что попытка обработать url провалилась, и
+// увеличивает счетчик попыток, использующийся в ShouldRetry.
+func (c *URLCache) RecordFailure(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.entries[url]
+	entry.FetchedAt = time.Now()
+	entry.Success = false
+	entry.Attempts++
+	c.entries[url] = entry
+}
+
+// ShouldRetry сообщает, стоит ли вообще пытаться обработать url: либо о нем
+// ничего не известно, либо в прошлый раз он обработался успешно, либо лимит
+// попыток ещё не исчерпан.
+func (c *URLCache) ShouldRetry(url string, maxAttempts int) bool {
+	entry, ok := c.Get(url)
+	if !ok || entry.Success {
+		return true
+	}
+
+	return entry.Attempts < maxAttempts
+}
+
+// Save сериализует кэш в path.
+func (c *URLCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, data, 0644)
+}
+
+// conditionalHeaders строит заголовки If-None-Match/If-Modified-Since по
+// сохраненной записи кэша, чтобы не скачивать страницу, если она не менялась.
+func conditionalHeaders(entry CacheEntry) map[string]string {
+	headers := make(map[string]string)
+	if entry.ETag != "" {
+		headers["If-None-Match"] = entry.ETag
+	}
+	if entry.LastModified != "" {
+		headers["If-Modified-Since"] = entry.LastModified
+	}
+	return headers
+}
+
+func hashContent(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func newTestCache(t *testing.T) *URLCache {
+	t.Helper()
+	return &URLCache{path: filepath.Join(t.TempDir(), "cache.json"), entries: make(map[string]CacheEntry)}
+}
+
+func TestURLCacheShouldRetry(t *testing.T) {
+	cache := newTestCache(t)
+	url := "https://example.com/page"
+
+	if !cache.ShouldRetry(url, 3) {
+		t.Fatal("неизвестный URL должен всегда пытаться обработаться")
+	}
+
+	cache.RecordFailure(url)
+	cache.RecordFailure(url)
+	if !cache.ShouldRetry(url, 3) {
+		t.Fatal("должен повторять попытку, пока attempts (2) < maxAttempts (3)")
+	}
+
+	cache.RecordFailure(url)
+	if cache.ShouldRetry(url, 3) {
+		t.Fatal("не должен повторять попытку после исчерпания лимита")
+	}
+
+	cache.RecordSuccess(url, "etag", "last-mod", "hash")
+	if !cache.ShouldRetry(url, 3) {
+		t.Fatal("успешно обработанный URL должен снова пытаться обработаться на следующем запуске")
+	}
+}
+
+func TestURLCacheRecordFailureIncrementsAttempts(t *testing.T) {
+	cache := newTestCache(t)
+	url := "https://example.com/page"
+
+	cache.RecordFailure(url)
+	entry, ok := cache.Get(url)
+	if !ok {
+		t.Fatal("ожидалась запись после RecordFailure")
+	}
+	if entry.Attempts != 1 || entry.Success {
+		t.Fatalf("неожиданная запись после первой неудачи: %+v", entry)
+	}
+
+	cache.RecordFailure(url)
+	entry, _ = cache.Get(url)
+	if entry.Attempts != 2 {
+		t.Fatalf("ожидался Attempts=2 после второй неудачи, получено %d", entry.Attempts)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+)
+
+// Fetcher отделяет получение страницы от её разбора, чтобы processURL не
+// зависел напрямую от net/http — это нужно для будущих реализаций (например,
+// рендеринг через headless-браузер для SPA). headers позволяет передать
+// условные заголовки (If-None-Match, If-Modified-Since) для кэша.
+type Fetcher interface {
+	Fetch(url string, headers map[string]string) (*http.Response, error)
+}
+
+// HTTPFetcher — реализация Fetcher поверх net/http с повторными попытками
+// и экспоненциальной задержкой при 5xx и 429 ответах.
+type HTTPFetcher struct {
+	Client     *http.Client
+	MaxRetries int
+	BaseDelay  time.Duration
+	UserAgent  string
+}
+
+// NewHTTPFetcher создает HTTPFetcher с настройками по умолчанию, который
+// представляется серверу как userAgent.
+func NewHTTPFetcher(userAgent string) *HTTPFetcher {
+	return &HTTPFetcher{
+		Client:     &http.Client{Timeout: 30 * time.Second},
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		UserAgent:  userAgent,
+	}
+}
+
+func (f *HTTPFetcher) Fetch(url string, headers map[string]string) (*http.Response, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= f.MaxRetries; attempt++ {
+		if attempt > 0 {
+			// Экспоненциальный бэкофф: 0.5s, 1s, 2s, ...
+			delay := f.BaseDelay * time.Duration(math.Pow(2, float64(attempt-1)))
+			time.Sleep(delay)
+		}
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if f.UserAgent != "" {
+			req.Header.Set("User-Agent", f.UserAgent)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := f.Client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("status %d", resp.StatusCode)
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("не удалось получить %s за %d попыток: %w", url, f.MaxRetries+1, lastErr)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// htmlToMarkdown переводит содержимое sel в Markdown: заголовки, параграфы,
+// списки, ссылки, картинки, код и таблицы — этого достаточно для статей базы
+// знаний, с которыми работает скрапер.
+func htmlToMarkdown(sel *goquery.Selection) string {
+	var b strings.Builder
+	renderNodes(&b, sel.Contents())
+	return strings.TrimSpace(collapseBlankLines(b.String()))
+}
+
+func renderNodes(b *strings.Builder, nodes *goquery.Selection) {
+	nodes.Each(func(_ int, node *goquery.Selection) {
+		renderNode(b, node)
+	})
+}
+
+func renderNode(b *strings.Builder, node *goquery.Selection) {
+	switch goquery.NodeName(node) {
+	case "#text":
+		b.WriteString(node.Text())
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		b.WriteString("\n" + strings.Repeat("#", headingLevel(node)) + " ")
+		renderNodes(b, node.Contents())
+		b.WriteString("\n\n")
+	case "p":
+		renderNodes(b, node.Contents())
+		b.WriteString("\n\n")
+	case "strong", "b":
+		b.WriteString("**")
+		renderNodes(b, node.Contents())
+		b.WriteString("**")
+	case "em", "i":
+		b.WriteString("_")
+		renderNodes(b, node.Contents())
+		b.WriteString("_")
+	case "code":
+		b.WriteString("`" + node.Text() + "`")
+	case "br":
+		b.WriteString("\n")
+	case "a":
+		href, _ := node.Attr("href")
+		b.WriteString("[" + strings.TrimSpace(node.Text()) + "](" + href + ")")
+	case "img":
+		src, _ := node.Attr("src")
+		alt, _ := node.Attr("alt")
+		b.WriteString("![" + alt + "](" + src + ")")
+	case "ul":
+		renderList(b, node, false)
+	case "ol":
+		renderList(b, node, true)
+	case "blockquote":
+		renderBlockquote(b, node)
+	case "pre":
+		renderCodeBlock(b, node)
+	case "table":
+		renderTable(b, node)
+	default:
+		renderNodes(b, node.Contents())
+	}
+}
+
+func headingLevel(node *goquery.Selection) int {
+	name := goquery.NodeName(node)
+	if len(name) == 2 && name[0] == 'h' {
+		return int(name[1] - '0')
+	}
+	return 1
+}
+
+func renderList(b *strings.Builder, node *goquery.Selection, ordered bool) {
+	i := 1
+	node.ChildrenFiltered("li").Each(func(_ int, li *goquery.Selection) {
+		marker := "-"
+		if ordered {
+			marker = fmt.Sprintf("%d.", i)
+		}
+		b.WriteString(marker + " ")
+		renderNodes(b, li.Contents())
+		b.WriteString("\n")
+		i++
+	})
+	b.WriteString("\n")
+}
+
+func renderBlockquote(b *strings.Builder, node *goquery.Selection) {
+	var inner strings.Builder
+	renderNodes(&inner, node.Contents())
+
+	for _, line := range strings.Split(strings.TrimSpace(inner.String()), "\n") {
+		b.WriteString("> " + line + "\n")
+	}
+	b.WriteString("\n")
+}
+
+func renderCodeBlock(b *strings.Builder, node *goquery.Selection) {
+	code := node.Find("code").Text()
+	if code == "" {
+		code = node.Text()
+	}
+	b.WriteString("```\n" + strings.TrimRight(code, "\n") + "\n```\n\n")
+}
+
+func renderTable(b *strings.Builder, node *goquery.Selection) {
+	node.Find("tr").Each(func(i int, row *goquery.Selection) {
+		var cells []string
+		row.Find("th, td").Each(func(_ int, cell *goquery.Selection) {
+			cells = append(cells, strings.TrimSpace(cell.Text()))
+		})
+
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", len(cells)) + "\n")
+		}
+	})
+	b.WriteString("\n")
+}
+
+var blankLinesRe = regexp.MustCompile(`\n{3,}`)
+
+func collapseBlankLines(s string) string {
+	return blankLinesRe.ReplaceAllString(s, "\n\n")
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestHtmlToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "heading and paragraph",
+			html: `<h1>Title</h1><p>Hello <strong>world</strong></p>`,
+			want: "# Title\n\nHello **world**",
+		},
+		{
+			name: "unordered list",
+			html: `<ul><li>one</li><li>two</li></ul>`,
+			want: "- one\n- two",
+		},
+		{
+			name: "ordered list",
+			html: `<ol><li>first</li><li>second</li></ol>`,
+			want: "1. first\n2. second",
+		},
+		{
+			name: "link and image",
+			html: `<p><a href="https://example.com">site</a> <img src="pic.png" alt="pic"></p>`,
+			want: "[site](https://example.com) ![pic](pic.png)",
+		},
+		{
+			name: "code block",
+			html: `<pre><code>fmt.Println("hi")</code></pre>`,
+			want: "```\nfmt.Println(\"hi\")\n```",
+		},
+		{
+			name: "blockquote",
+			html: `<blockquote>quoted text</blockquote>`,
+			want: "> quoted text",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel := mustParseBody(t, tt.html)
+
+			got := htmlToMarkdown(sel)
+			if got != tt.want {
+				t.Errorf("htmlToMarkdown(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func mustParseBody(t *testing.T, html string) *goquery.Selection {
+	t.Helper()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parse html: %v", err)
+	}
+
+	return doc.Find("body")
+}
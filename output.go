@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// OutputFormat перечисляет форматы, в которых можно сохранить статью.
+type OutputFormat string
+
+const (
+	FormatMarkdown OutputFormat = "md"
+	FormatHTML     OutputFormat = "html"
+	FormatJSON     OutputFormat = "json"
+)
+
+// Heading — один заголовок статьи вместе с уровнем вложенности (1 для h1,
+// 2 для h2 и т.д.), из которого на стороне потребителя легко восстановить
+// дерево.
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+}
+
+// jsonArticle — структурированное представление статьи для --output-format=json.
+type jsonArticle struct {
+	Title    string    `json:"title"`
+	URL      string    `json:"url"`
+	LastMod  string    `json:"lastmod,omitempty"`
+	Headings []Heading `json:"headings"`
+	Body     string    `json:"body"`
+}
+
+// renderArticle собирает содержимое файла в заданном формате и возвращает
+// вместе с ним расширение файла, в который его нужно сохранить.
+func renderArticle(format OutputFormat, profile *SiteProfile, title, url, lastMod string, bodySel *goquery.Selection) (content, ext string, err error) {
+	switch format {
+	case FormatHTML:
+		bodyHTML, err := bodySel.Html()
+		if err != nil {
+			return "", "", err
+		}
+
+		content, err := profile.Render(title, url, strings.TrimSpace(bodyHTML))
+		return content, "html", err
+
+	case FormatJSON:
+		article := jsonArticle{
+			Title:    title,
+			URL:      url,
+			LastMod:  lastMod,
+			Headings: extractHeadings(bodySel),
+			Body:     strings.TrimSpace(bodySel.Text()),
+		}
+
+		data, err := json.MarshalIndent(article, "", "  ")
+		return string(data), "json", err
+
+	default:
+		content, err := profile.Render(title, url, htmlToMarkdown(bodySel))
+		return content, "md", err
+	}
+}
+
+// extractHeadings собирает h1..h6 внутри bodySel в плоский список с
+// уровнями вложенности.
+func extractHeadings(bodySel *goquery.Selection) []Heading {
+	var headings []Heading
+	bodySel.Find("h1, h2, h3, h4, h5, h6").Each(func(_ int, h *goquery.Selection) {
+		headings = append(headings, Heading{
+			Level: headingLevel(h),
+			Text:  strings.TrimSpace(h.Text()),
+		})
+	})
+	return headings
+}
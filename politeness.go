@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+)
+
+// Politeness отвечает за соблюдение robots.txt: кэширует правила по хосту,
+// решает, можно ли скачивать конкретный URL, и сообщает Crawl-delay, если
+// сайт его указал. С --respect-robots=false все проверки отключаются — это
+// нужно, когда оператор сам владеет целевым сайтом.
+type Politeness struct {
+	mu            sync.Mutex
+	userAgent     string
+	client        *http.Client
+	groups        map[string]*robotstxt.Group
+	respectRobots bool
+}
+
+// NewPoliteness создает Politeness, который запрашивает robots.txt под
+// именем userAgent.
+func NewPoliteness(userAgent string, respectRobots bool) *Politeness {
+	return &Politeness{
+		userAgent:     userAgent,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		groups:        make(map[string]*robotstxt.Group),
+		respectRobots: respectRobots,
+	}
+}
+
+// Allowed сообщает, можно ли скачивать rawURL согласно robots.txt его хоста.
+func (p *Politeness) Allowed(rawURL string) bool {
+	if !p.respectRobots {
+		return true
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return true
+	}
+
+	group, err := p.groupFor(u)
+	if err != nil || group == nil {
+		// Не удалось получить или разобрать robots.txt — не блокируем обход из-за этого.
+		return true
+	}
+
+	return group.Test(u.Path)
+}
+
+// CrawlDelay возвращает Crawl-delay для хоста rawURL, если сайт его указал
+// в robots.txt.
+func (p *Politeness) CrawlDelay(rawURL string) (time.Duration, bool) {
+	if !p.respectRobots {
+		return 0, false
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, false
+	}
+
+	group, err := p.groupFor(u)
+	if err != nil || group == nil || group.CrawlDelay == 0 {
+		return 0, false
+	}
+
+	return group.CrawlDelay, true
+}
+
+func (p *Politeness) groupFor(u *url.URL) (*robotstxt.Group, error) {
+	p.mu.Lock()
+	group, ok := p.groups[u.Host]
+	p.mu.Unlock()
+	if ok {
+		return group, nil
+	}
+
+	group, err := p.fetchGroup(u)
+
+	p.mu.Lock()
+	p.groups[u.Host] = group
+	p.mu.Unlock()
+
+	return group, err
+}
+
+func (p *Politeness) fetchGroup(u *url.URL) (*robotstxt.Group, error) {
+	robotsURL := fmt.Sprintf("%s://%s/robots.txt", u.Scheme, u.Host)
+
+	req, err := http.NewRequest(http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", p.userAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := robotstxt.FromResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.FindGroup(p.userAgent), nil
+}
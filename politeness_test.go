@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPolitenessAllowed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("User-agent: *\nDisallow: /private/\nCrawl-delay: 2\n"))
+	}))
+	defer srv.Close()
+
+	p := NewPoliteness("TestBot/1.0", true)
+
+	if !p.Allowed(srv.URL + "/public/page") {
+		t.Error("/public/page должен быть разрешен")
+	}
+	if p.Allowed(srv.URL + "/private/page") {
+		t.Error("/private/page должен быть запрещен")
+	}
+
+	delay, ok := p.CrawlDelay(srv.URL + "/public/page")
+	if !ok || delay != 2*time.Second {
+		t.Errorf("CrawlDelay = %v, %v, want 2s, true", delay, ok)
+	}
+}
+
+func TestPolitenessRespectRobotsDisabled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("User-agent: *\nDisallow: /\n"))
+	}))
+	defer srv.Close()
+
+	p := NewPoliteness("TestBot/1.0", false)
+
+	if !p.Allowed(srv.URL + "/anything") {
+		t.Error("с --respect-robots=false всё должно быть разрешено")
+	}
+	if _, ok := p.CrawlDelay(srv.URL + "/anything"); ok {
+		t.Error("с --respect-robots=false CrawlDelay не должен сообщаться")
+	}
+}
+
+func TestPolitenessUnreachableRobotsAllowsByDefault(t *testing.T) {
+	p := NewPoliteness("TestBot/1.0", true)
+
+	if !p.Allowed("http://127.0.0.1:1/page") {
+		t.Error("если robots.txt недоступен, обход не должен блокироваться")
+	}
+}
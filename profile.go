@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultOutputTemplate используется, если профиль не задает свой output_template.
+const defaultOutputTemplate = "# {{.Title}}\n\n**URL:** {{.URL}}\n\n{{.Body}}"
+
+// SiteProfile описывает, как вытащить статью с конкретного раздела сайта:
+// под каким префиксом URL он живет, где искать заголовок и тело статьи, какие
+// элементы вырезать перед сохранением и как собрать итоговый файл.
+type SiteProfile struct {
+	Name           string   `yaml:"name"`
+	URLPrefix      string   `yaml:"url_prefix"`
+	TitleSelector  string   `yaml:"title_selector"`
+	BodySelector   string   `yaml:"body_selector"`
+	StripSelectors []string `yaml:"strip_selectors"`
+	OutputTemplate string   `yaml:"output_template"`
+}
+
+// articleData — данные, доступные внутри OutputTemplate профиля.
+type articleData struct {
+	Title string
+	URL   string
+	Body  string
+}
+
+// LoadSiteProfile читает один SiteProfile из YAML или JSON файла (JSON —
+// частный случай YAML, поэтому парсер общий для обоих форматов).
+func LoadSiteProfile(path string) (*SiteProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile SiteProfile
+	if err := yaml.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("разбор профиля %s: %w", path, err)
+	}
+
+	if profile.OutputTemplate == "" {
+		profile.OutputTemplate = defaultOutputTemplate
+	}
+
+	if profile.Name == "" {
+		profile.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return &profile, nil
+}
+
+// Render собирает содержимое выходного файла из OutputTemplate профиля.
+func (p *SiteProfile) Render(title, url, body string) (string, error) {
+	tmpl, err := template.New(p.Name).Parse(p.OutputTemplate)
+	if err != nil {
+		return "", fmt.Errorf("шаблон профиля %s: %w", p.Name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, articleData{Title: title, URL: url, Body: body}); err != nil {
+		return "", fmt.Errorf("применение шаблона профиля %s: %w", p.Name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// Registry хранит набор профилей и находит подходящий по префиксу URL, что
+// позволяет одному бинарнику обслуживать сразу несколько разделов сайта или
+// несколько сайтов за один прогон.
+type Registry struct {
+	profiles []*SiteProfile
+}
+
+// LoadRegistry читает все *.yaml/*.yml/*.json файлы из dir как SiteProfile.
+func LoadRegistry(dir string) (*Registry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("чтение директории профилей %s: %w", dir, err)
+	}
+
+	reg := &Registry{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+
+		profile, err := LoadSiteProfile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		reg.profiles = append(reg.profiles, profile)
+	}
+
+	return reg, nil
+}
+
+// Match находит первый профиль, чей URLPrefix совпадает с началом url.
+func (r *Registry) Match(url string) *SiteProfile {
+	for _, p := range r.profiles {
+		if strings.HasPrefix(url, p.URLPrefix) {
+			return p
+		}
+	}
+	return nil
+}
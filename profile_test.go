@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSiteProfileDefaults(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "my-section.yaml")
+	content := "url_prefix: \"https://example.com/docs/\"\ntitle_selector: \"h1\"\nbody_selector: \"div.content\"\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := LoadSiteProfile(path)
+	if err != nil {
+		t.Fatalf("LoadSiteProfile: %v", err)
+	}
+
+	if profile.Name != "my-section" {
+		t.Errorf("Name = %q, want %q (из имени файла)", profile.Name, "my-section")
+	}
+	if profile.OutputTemplate != defaultOutputTemplate {
+		t.Errorf("OutputTemplate = %q, want defaultOutputTemplate", profile.OutputTemplate)
+	}
+}
+
+func TestLoadSiteProfileExplicitValues(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "whatever.json")
+	content := `{"name": "custom", "url_prefix": "https://example.com/", "output_template": "{{.Title}}"}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := LoadSiteProfile(path)
+	if err != nil {
+		t.Fatalf("LoadSiteProfile: %v", err)
+	}
+
+	if profile.Name != "custom" {
+		t.Errorf("Name = %q, want %q (явно задано в файле)", profile.Name, "custom")
+	}
+	if profile.OutputTemplate != "{{.Title}}" {
+		t.Errorf("OutputTemplate = %q, want %q (явно задано в файле)", profile.OutputTemplate, "{{.Title}}")
+	}
+}
+
+func TestRegistryMatch(t *testing.T) {
+	reg := &Registry{profiles: []*SiteProfile{
+		{Name: "docs", URLPrefix: "https://example.com/docs/"},
+		{Name: "instructions", URLPrefix: "https://example.com/instructions/"},
+	}}
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://example.com/docs/faq", "docs"},
+		{"https://example.com/instructions/faq", "instructions"},
+		{"https://example.com/other/page", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			profile := reg.Match(tt.url)
+			if tt.want == "" {
+				if profile != nil {
+					t.Errorf("Match(%q) = %q, want no match", tt.url, profile.Name)
+				}
+				return
+			}
+			if profile == nil || profile.Name != tt.want {
+				t.Errorf("Match(%q) = %v, want %q", tt.url, profile, tt.want)
+			}
+		})
+	}
+}
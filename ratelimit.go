@@ -0,0 +1,126 @@
+package main
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// hostRateLimiter — простой token-bucket лимитер запросов для каждого хоста
+// отдельно, чтобы параллельные воркеры не заваливали один и тот же сайт
+// запросами, даже если sitemap ссылается сразу на несколько источников.
+type hostRateLimiter struct {
+	mu       sync.Mutex
+	buckets  map[string]*tokenBucket
+	interval time.Duration
+	burst    int
+}
+
+func newHostRateLimiter(interval time.Duration, burst int) *hostRateLimiter {
+	return &hostRateLimiter{
+		buckets:  make(map[string]*tokenBucket),
+		interval: interval,
+		burst:    burst,
+	}
+}
+
+// Wait блокируется, пока не освободится токен для хоста из rawURL.
+func (l *hostRateLimiter) Wait(rawURL string) {
+	b := l.bucketFor(hostOf(rawURL))
+	b.take()
+}
+
+// SetDelay переопределяет интервал пополнения токенов для конкретного
+// хоста — например, когда его robots.txt просит Crawl-delay длиннее, чем
+// дефолтная задержка краулера.
+func (l *hostRateLimiter) SetDelay(host string, interval time.Duration) {
+	l.bucketFor(host).setInterval(interval)
+}
+
+func (l *hostRateLimiter) bucketFor(host string) *tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[host]
+	if !ok {
+		b = newTokenBucket(l.interval, l.burst)
+		l.buckets[host] = b
+	}
+	return b
+}
+
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Host
+}
+
+// tokenBucket восполняет один токен раз в interval, максимум burst токенов
+// накапливается про запас.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   int
+	burst    int
+	interval time.Duration
+	last     time.Time
+}
+
+func newTokenBucket(interval time.Duration, burst int) *tokenBucket {
+	return &tokenBucket{
+		tokens:   burst,
+		burst:    burst,
+		interval: interval,
+		last:     time.Now(),
+	}
+}
+
+func (b *tokenBucket) setInterval(interval time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.interval = interval
+}
+
+func (b *tokenBucket) take() {
+	for {
+		b.mu.Lock()
+
+		if b.interval <= 0 {
+			// interval <= 0 значит лимит отключен (--delay 0) — пропускаем
+			// без ожидания, а не копим токены, которые никогда не пополнятся.
+			b.mu.Unlock()
+			return
+		}
+
+		b.refillLocked()
+
+		if b.tokens > 0 {
+			b.tokens--
+			b.mu.Unlock()
+			return
+		}
+
+		wait := b.interval
+		b.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+func (b *tokenBucket) refillLocked() {
+	if b.interval <= 0 {
+		return
+	}
+
+	elapsed := time.Since(b.last)
+	refill := int(elapsed / b.interval)
+	if refill <= 0 {
+		return
+	}
+
+	b.tokens += refill
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = b.last.Add(time.Duration(refill) * b.interval)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Renderer отдает готовый HTML документ по URL. Единственная реализация —
+// ChromedpRenderer, который поднимает headless Chrome и ждет появления всех
+// waitSelectors — это нужно для SPA, где разметка собирается JavaScript'ом
+// уже в браузере, и goquery.NewDocumentFromReader на сыром HTML (через
+// обычный Fetcher) возвращает пустые селекторы. processURL сначала пробует
+// Fetcher напрямую и обращается к Renderer только как к запасному варианту,
+// передавая лишь те селекторы, которых не хватило в первой попытке.
+type Renderer interface {
+	Render(url string, waitSelectors ...string) (string, error)
+}
+
+// ChromedpRenderer рендерит страницу в headless Chrome и дожидается
+// появления каждого из waitSelectors в DOM, прежде чем забрать итоговый HTML.
+type ChromedpRenderer struct {
+	Timeout time.Duration
+}
+
+func NewChromedpRenderer() *ChromedpRenderer {
+	return &ChromedpRenderer{Timeout: 20 * time.Second}
+}
+
+func (r *ChromedpRenderer) Render(url string, waitSelectors ...string) (string, error) {
+	ctx, cancel := chromedp.NewContext(context.Background())
+	defer cancel()
+
+	ctx, cancelTimeout := context.WithTimeout(ctx, r.Timeout)
+	defer cancelTimeout()
+
+	tasks := chromedp.Tasks{chromedp.Navigate(url)}
+	for _, sel := range waitSelectors {
+		tasks = append(tasks, chromedp.WaitVisible(sel, chromedp.ByQuery))
+	}
+
+	var html string
+	tasks = append(tasks, chromedp.OuterHTML("html", &html))
+
+	if err := chromedp.Run(ctx, tasks); err != nil {
+		return "", fmt.Errorf("chromedp рендер %s: %w", url, err)
+	}
+
+	return html, nil
+}
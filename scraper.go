@@ -1,7 +1,8 @@
 package main
 
 import (
-	"encoding/xml"
+	"bytes"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -10,32 +11,55 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
-type URLSet struct {
-	XMLName xml.Name `xml:"urlset"`
-	URLs    []URL    `xml:"url"`
-}
-
-type URL struct {
-	Loc string `xml:"loc"`
-}
-
 func main() {
 	// Параметры конфигурации
-	maxPages := 0                                             // Максимальное количество страниц для скачивания (0 = без ограничений)
-	requestDelay := 1 * time.Second                           // Задержка между запросами
-	targetPrefix := "https://nethouse.ru/about/instructions/" // Фильтр URL
+	maxPages := 0 // Максимальное количество страниц для скачивания (0 = без ограничений)
+
+	workers := flag.Int("workers", 5, "количество воркеров, скачивающих страницы параллельно")
+	requestDelay := flag.Duration("delay", 1*time.Second, "минимальный интервал между запросами к одному хосту")
+	sinceStr := flag.String("since", "", "пропускать страницы, у которых <lastmod> не позже этой даты (RFC3339 или YYYY-MM-DD)")
+	profilesDir := flag.String("profiles", "profiles", "директория с конфигами SiteProfile (YAML/JSON)")
+	outputFormatStr := flag.String("output-format", "md", "формат сохранения статей: md, html или json")
+	cachePath := flag.String("cache", filepath.Join("data", ".cache.json"), "файл с состоянием предыдущих обходов для возобновляемого краулинга")
+	maxRetries := flag.Int("max-retries", 3, "сколько раз повторно пытаться обработать URL, упавший в прошлый запуск")
+	userAgent := flag.String("user-agent", "KBScraperBot/1.0 (+https://github.com/total-mistake/manticoresearch-tester)", "User-Agent, отправляемый вместе с запросами")
+	respectRobots := flag.Bool("respect-robots", true, "проверять robots.txt перед скачиванием страницы (отключите, если вы владелец сайта)")
+	flag.Parse()
+
+	format := OutputFormat(*outputFormatStr)
+	switch format {
+	case FormatMarkdown, FormatHTML, FormatJSON:
+	default:
+		log.Fatalf("Неизвестный --output-format %q, ожидается md, html или json", *outputFormatStr)
+	}
 
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run scraper.go <sitemap_url>")
+	if *workers < 1 {
+		log.Fatalf("--workers должен быть не меньше 1, получено %d", *workers)
+	}
+
+	args := flag.Args()
+	if len(args) < 1 {
+		fmt.Println("Usage: go run . [flags] <sitemap_url>")
+		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
-	sitemapURL := os.Args[1]
+	sitemapURL := args[0]
+
+	var since time.Time
+	if *sinceStr != "" {
+		parsed, err := parseLastMod(*sinceStr)
+		if err != nil {
+			log.Fatal("Некорректное значение --since:", err)
+		}
+		since = parsed
+	}
 
 	// Создаем папку data если не существует
 	outputDir := "data"
@@ -43,104 +67,249 @@ func main() {
 		log.Fatal("Ошибка создания директории:", err)
 	}
 
-	// Получаем все URL из sitemap.xml
-	urls, err := getSitemapURLs(sitemapURL)
+	// Загружаем профили сайтов — они задают селекторы и фильтр URL вместо
+	// того, чтобы быть зашитыми в код
+	registry, err := LoadRegistry(*profilesDir)
+	if err != nil {
+		log.Fatal("Ошибка загрузки профилей:", err)
+	}
+
+	// Загружаем состояние предыдущих обходов, чтобы возобновить краулинг, а
+	// не начинать каждый раз с нуля
+	cache, err := LoadURLCache(*cachePath)
+	if err != nil {
+		log.Fatal("Ошибка загрузки кэша:", err)
+	}
+
+	// Получаем все URL из sitemap.xml (с рекурсивным обходом sitemap index)
+	pages, err := getSitemapURLs(sitemapURL, since)
 	if err != nil {
 		log.Fatal("Ошибка получения sitemap:", err)
 	}
 
-	// Фильтруем URL, которые начинаются с нужного префикса
-	var filteredURLs []string
-	for _, url := range urls {
-		if strings.HasPrefix(url, targetPrefix) {
-			filteredURLs = append(filteredURLs, url)
+	politeness := NewPoliteness(*userAgent, *respectRobots)
+
+	// Фильтруем URL: оставляем только те, для которых нашелся профиль,
+	// которые разрешены robots.txt и которые либо ещё не пытались
+	// обрабатывать, либо не исчерпали лимит повторных попыток после
+	// прошлой неудачи
+	var filteredPages []PageRef
+	for _, page := range pages {
+		if registry.Match(page.URL) == nil {
+			continue
 		}
+		if !politeness.Allowed(page.URL) {
+			fmt.Printf("Запрещено robots.txt, пропускаем: %s\n", page.URL)
+			continue
+		}
+		if !cache.ShouldRetry(page.URL, *maxRetries) {
+			continue
+		}
+		filteredPages = append(filteredPages, page)
 	}
 
-	fmt.Printf("Найдено %d страниц для скачивания (ограничение: %d)\n", len(filteredURLs), maxPages)
+	fmt.Printf("Найдено %d страниц для скачивания (ограничение: %d)\n", len(filteredPages), maxPages)
 
 	// Применяем ограничение на количество страниц
-	if maxPages > 0 && len(filteredURLs) > maxPages {
-		filteredURLs = filteredURLs[:maxPages]
+	if maxPages > 0 && len(filteredPages) > maxPages {
+		filteredPages = filteredPages[:maxPages]
+	}
+
+	deps := crawlDeps{
+		Fetcher:    NewHTTPFetcher(*userAgent),
+		JSRenderer: NewChromedpRenderer(),
+		Registry:   registry,
+		Format:     format,
+		Cache:      cache,
+		Politeness: politeness,
+	}
+	crawl(filteredPages, *workers, *requestDelay, deps)
+
+	if err := cache.Save(); err != nil {
+		log.Println("Ошибка сохранения кэша:", err)
+	}
+
+	fmt.Println("Scraping completed!")
+}
+
+// crawlDeps собирает в одном месте все, что нужно processURL помимо самой
+// страницы — так сигнатура не разрастается с каждой новой возможностью.
+type crawlDeps struct {
+	Fetcher    Fetcher
+	JSRenderer Renderer
+	Registry   *Registry
+	Format     OutputFormat
+	Cache      *URLCache
+	Politeness *Politeness
+}
+
+// crawl раздает страницы пулу из workers воркеров через общий канал и
+// ограничивает скорость запросов к каждому хосту отдельно, чтобы
+// многостраничный обход не бил по одному origin быстрее, чем он готов
+// отвечать. Если сайт указал Crawl-delay в robots.txt, лимитер хоста
+// подстраивается под него.
+func crawl(pages []PageRef, workers int, requestDelay time.Duration, deps crawlDeps) {
+	jobs := make(chan PageRef)
+	limiter := newHostRateLimiter(requestDelay, 1)
+	applyCrawlDelays(pages, deps.Politeness, limiter)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				limiter.Wait(page.URL)
+
+				profile := deps.Registry.Match(page.URL)
+				if profile == nil {
+					fmt.Printf("Нет подходящего профиля для %s, пропускаем\n", page.URL)
+					continue
+				}
+
+				if err := processURL(deps, profile, page); err != nil {
+					fmt.Printf("Error processing %s: %v\n", page.URL, err)
+					deps.Cache.RecordFailure(page.URL)
+				}
+			}
+		}()
+	}
+
+	for i, page := range pages {
+		fmt.Printf("Queueing %d/%d: %s\n", i+1, len(pages), page.URL)
+		jobs <- page
 	}
+	close(jobs)
 
-	// Обрабатываем каждый URL
-	for i, url := range filteredURLs {
-		fmt.Printf("Processing %d/%d: %s\n", i+1, len(filteredURLs), url)
+	wg.Wait()
+}
 
-		if err := processURL(url); err != nil {
-			fmt.Printf("Error processing %s: %v\n", url, err)
+// applyCrawlDelays проверяет Crawl-delay в robots.txt для каждого хоста,
+// встречающегося в pages, и настраивает под него лимитер — один раз на
+// хост, а не на каждый URL.
+func applyCrawlDelays(pages []PageRef, politeness *Politeness, limiter *hostRateLimiter) {
+	seen := make(map[string]bool)
+	for _, page := range pages {
+		host := hostOf(page.URL)
+		if seen[host] {
 			continue
 		}
+		seen[host] = true
 
-		// Задержка между запросами
-		time.Sleep(requestDelay)
+		if delay, ok := politeness.CrawlDelay(page.URL); ok {
+			limiter.SetDelay(host, delay)
+		}
 	}
-
-	fmt.Println("Scraping completed!")
 }
 
-func getSitemapURLs(sitemapURL string) ([]string, error) {
-	resp, err := http.Get(sitemapURL)
+func processURL(deps crawlDeps, profile *SiteProfile, page PageRef) error {
+	url := page.URL
+
+	var headers map[string]string
+	if entry, ok := deps.Cache.Get(url); ok {
+		headers = conditionalHeaders(entry)
+	}
+
+	// Сначала пробуем обычный HTTP-запрос без выполнения JS
+	resp, err := deps.Fetcher.Fetch(url, headers)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		fmt.Printf("Страница не изменилась, пропускаем: %s\n", url)
+		return nil
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var urlset URLSet
-	if err := xml.Unmarshal(body, &urlset); err != nil {
-		return nil, err
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return err
 	}
 
-	var urls []string
-	for _, url := range urlset.URLs {
-		urls = append(urls, url.Loc)
+	title, bodySel := extractArticle(doc, profile)
+
+	// Сайт оказался SPA и нужная разметка появляется только после выполнения
+	// JS — пробуем ещё раз через headless Chrome. Ждем появления именно тех
+	// селекторов, которых не хватило: на гибридных страницах заголовок может
+	// быть отрендерен на сервере, а тело — подгружаться JS позже (или
+	// наоборот), и ожидание только одного из них могло бы вернуть управление
+	// раньше, чем второй успеет появиться в DOM.
+	if (title == "" || bodySel == nil) && deps.JSRenderer != nil {
+		var waitSelectors []string
+		if title == "" {
+			waitSelectors = append(waitSelectors, profile.TitleSelector)
+		}
+		if bodySel == nil {
+			waitSelectors = append(waitSelectors, profile.BodySelector)
+		}
+
+		html, err := deps.JSRenderer.Render(url, waitSelectors...)
+		if err != nil {
+			return fmt.Errorf("JS fallback: %w", err)
+		}
+		body = []byte(html)
+
+		doc, err = goquery.NewDocumentFromReader(strings.NewReader(html))
+		if err != nil {
+			return err
+		}
+		title, bodySel = extractArticle(doc, profile)
 	}
 
-	return urls, nil
-}
+	if title == "" {
+		return fmt.Errorf("title not found")
+	}
+	if bodySel == nil {
+		return fmt.Errorf("article body not found")
+	}
 
-func processURL(url string) error {
-	// Загружаем страницу
-	resp, err := http.Get(url)
+	// Формируем содержимое файла в выбранном формате
+	content, ext, err := renderArticle(deps.Format, profile, title, url, page.LastMod, bodySel)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	// Парсим HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
+	// Сохраняем файл в поддиректорию профиля — так страницы из разных
+	// разделов (или сайтов), у которых совпал последний сегмент URL, не
+	// затирают чужую статью
+	outDir := filepath.Join("data", profile.Name)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
 		return err
 	}
 
-	// Извлекаем данные с HTML тегами
-	titleHtml, _ := doc.Find("article.help-article__body h1.help-article__title").Html()
-	title := strings.TrimSpace(titleHtml)
-	if title == "" {
-		return fmt.Errorf("title not found")
+	filename := createFilename(url)
+	outPath := filepath.Join(outDir, filename+"."+ext)
+	if err := os.WriteFile(outPath, []byte(content), 0644); err != nil {
+		return err
 	}
 
-	articleBodyHtml, _ := doc.Find("article.help-article__body div.help-article__main").Html()
-	articleBody := strings.TrimSpace(articleBodyHtml)
-	if articleBody == "" {
-		return fmt.Errorf("article body not found")
+	deps.Cache.RecordSuccess(url, resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"), hashContent(body))
+
+	return nil
+}
+
+// extractArticle достает заголовок и выборку тела статьи из doc,
+// предварительно вырезая из него элементы из profile.StripSelectors.
+// bodySel равен nil, если тело статьи не найдено.
+func extractArticle(doc *goquery.Document, profile *SiteProfile) (title string, bodySel *goquery.Selection) {
+	for _, sel := range profile.StripSelectors {
+		doc.Find(sel).Remove()
 	}
 
-	// Создаем имя файла из URL
-	filename := createFilename(url)
+	title = strings.TrimSpace(doc.Find(profile.TitleSelector).Text())
 
-	// Формируем содержимое MD файла
-	content := fmt.Sprintf("# %s\n\n**URL:** %s\n\n%s", title, url, strings.TrimSpace(articleBody))
+	body := doc.Find(profile.BodySelector)
+	if body.Length() == 0 {
+		return title, nil
+	}
 
-	// Сохраняем файл
-	filepath := filepath.Join("data", filename+".md")
-	return os.WriteFile(filepath, []byte(content), 0644)
+	return title, body
 }
 
 func createFilename(url string) string {
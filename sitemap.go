@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// URLSet — обычный sitemap со списком страниц.
+type URLSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	URLs    []URL    `xml:"url"`
+}
+
+// URL описывает одну запись <url> в sitemap.
+type URL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// SitemapIndex — корневой документ <sitemapindex>, ссылающийся на другие
+// sitemap-файлы вместо того, чтобы перечислять страницы напрямую.
+type SitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Sitemaps []SitemapRef `xml:"sitemap"`
+}
+
+// SitemapRef — одна запись <sitemap> внутри sitemap index.
+type SitemapRef struct {
+	Loc string `xml:"loc"`
+}
+
+// PageRef — одна страница из sitemap вместе с её исходным <lastmod>, чтобы
+// эта информация не терялась после фильтрации по профилю и могла попасть в
+// структурированный JSON-вывод.
+type PageRef struct {
+	URL     string
+	LastMod string
+}
+
+// getSitemapURLs возвращает список страниц из sitemapURL. Поддерживает как
+// плоский <urlset>, так и <sitemapindex> (рекурсивно обходит дочерние
+// sitemap), а также прозрачно разжимает .xml.gz. Если since не нулевое,
+// страницы без <lastmod> позже since пропускаются.
+func getSitemapURLs(sitemapURL string, since time.Time) ([]PageRef, error) {
+	body, err := fetchSitemapBody(sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.Contains(body, []byte("<sitemapindex")) {
+		return getSitemapIndexURLs(body, since)
+	}
+
+	return getURLSetURLs(body, since)
+}
+
+func getSitemapIndexURLs(body []byte, since time.Time) ([]PageRef, error) {
+	var index SitemapIndex
+	if err := xml.Unmarshal(body, &index); err != nil {
+		return nil, err
+	}
+
+	var all []PageRef
+	for _, sm := range index.Sitemaps {
+		pages, err := getSitemapURLs(sm.Loc, since)
+		if err != nil {
+			return nil, fmt.Errorf("дочерний sitemap %s: %w", sm.Loc, err)
+		}
+		all = append(all, pages...)
+	}
+
+	return all, nil
+}
+
+func getURLSetURLs(body []byte, since time.Time) ([]PageRef, error) {
+	var urlset URLSet
+	if err := xml.Unmarshal(body, &urlset); err != nil {
+		return nil, err
+	}
+
+	var pages []PageRef
+	for _, u := range urlset.URLs {
+		if !since.IsZero() && !u.modifiedAfter(since) {
+			continue
+		}
+		pages = append(pages, PageRef{URL: u.Loc, LastMod: u.LastMod})
+	}
+
+	return pages, nil
+}
+
+// modifiedAfter сообщает, был ли <lastmod> указан и приходится ли он позже
+// since. Страница без <lastmod> считается всегда подходящей — у нас просто
+// нет данных, чтобы её отфильтровать.
+func (u URL) modifiedAfter(since time.Time) bool {
+	if u.LastMod == "" {
+		return true
+	}
+
+	lastMod, err := parseLastMod(u.LastMod)
+	if err != nil {
+		return true
+	}
+
+	return lastMod.After(since)
+}
+
+// parseLastMod разбирает дату в одном из форматов, которые встречаются в
+// <lastmod> (полный RFC3339 или просто YYYY-MM-DD), и используется также для
+// разбора флага --since.
+func parseLastMod(value string) (time.Time, error) {
+	layouts := []string{time.RFC3339, "2006-01-02"}
+
+	var lastErr error
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+		lastErr = err
+	}
+
+	return time.Time{}, lastErr
+}
+
+// fetchSitemapBody скачивает sitemapURL и при необходимости разжимает
+// gzip — либо по заголовку Content-Encoding, либо по расширению .gz.
+func fetchSitemapBody(sitemapURL string) ([]byte, error) {
+	resp, err := http.Get(sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" || strings.HasSuffix(sitemapURL, ".gz") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("распаковка gzip: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLastMod(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "RFC3339",
+			input: "2026-01-15T10:00:00Z",
+			want:  time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "date only",
+			input: "2026-01-15",
+			want:  time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "garbage",
+			input:   "not-a-date",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLastMod(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseLastMod(%q) expected error, got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseLastMod(%q) unexpected error: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseLastMod(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLModifiedAfter(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		url  URL
+		want bool
+	}{
+		{
+			name: "no lastmod always passes",
+			url:  URL{Loc: "https://example.com/a"},
+			want: true,
+		},
+		{
+			name: "modified after since",
+			url:  URL{Loc: "https://example.com/b", LastMod: "2026-02-01"},
+			want: true,
+		},
+		{
+			name: "modified before since",
+			url:  URL{Loc: "https://example.com/c", LastMod: "2025-12-01"},
+			want: false,
+		},
+		{
+			name: "unparsable lastmod always passes",
+			url:  URL{Loc: "https://example.com/d", LastMod: "garbage"},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.url.modifiedAfter(since); got != tt.want {
+				t.Errorf("modifiedAfter(%v) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}